@@ -0,0 +1,153 @@
+package printer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+// AutoPrinterOptions groups the options required by every
+// printer the NewAutoPrinter dispatcher may delegate to.
+type AutoPrinterOptions struct {
+	OfficeOptions OfficePrinterOptions
+	EbookOptions  EbookPrinterOptions
+	TextOptions   TextPrinterOptions
+	// PdfFormat, when set, requests an archival PDF/A
+	// conversion instead of a plain PDF. See PdfA1a, PdfA2b and
+	// PdfA3b. Applied once on the final destination rather than
+	// left to each sub-printer: when a request mixes file
+	// types, the outer pdftk merge below builds a brand-new
+	// catalog that does not carry forward any per-group
+	// OutputIntents/Metadata entries.
+	PdfFormat string
+	// PdfViewerPreferences lets callers control the initial
+	// presentation of the document in a PDF viewer. Applied
+	// once on the final destination, for the same reason as
+	// PdfFormat above.
+	PdfViewerPreferences PdfViewerPreferences
+}
+
+// DefaultAutoPrinterOptions returns the default
+// Auto Printer options.
+func DefaultAutoPrinterOptions(config conf.Config) AutoPrinterOptions {
+	return AutoPrinterOptions{
+		OfficeOptions:        DefaultOfficePrinterOptions(config),
+		EbookOptions:         DefaultEbookPrinterOptions(config),
+		TextOptions:          DefaultTextPrinterOptions(config),
+		PdfFormat:            "",
+		PdfViewerPreferences: DefaultPdfViewerPreferences(),
+	}
+}
+
+// NewAutoPrinter returns a Printer which dispatches each
+// input file to the TextPrinter, the EbookPrinter or the
+// Office printer depending on its extension, then merges
+// the resulting PDFs, text first, then ebook, then office —
+// not necessarily the original file order.
+//
+// TODO: this tree has no handler/route package to route
+// requests through NewAutoPrinter; until that package exists
+// here, a ".txt" or ebook upload hitting whatever route calls
+// NewOfficePrinter directly still gets an unoconv-formatted
+// result instead of the TextPrinter/EbookPrinter one.
+func NewAutoPrinter(logger xlog.Logger, fpaths []string, opts AutoPrinterOptions) Printer {
+	var textFpaths, ebookFpaths, officeFpaths []string
+	for _, fpath := range fpaths {
+		switch {
+		case IsTextFilename(fpath):
+			textFpaths = append(textFpaths, fpath)
+		case IsEbookFilename(fpath):
+			ebookFpaths = append(ebookFpaths, fpath)
+		default:
+			officeFpaths = append(officeFpaths, fpath)
+		}
+	}
+	return autoPrinter{
+		logger:       logger,
+		textFpaths:   textFpaths,
+		ebookFpaths:  ebookFpaths,
+		officeFpaths: officeFpaths,
+		opts:         opts,
+	}
+}
+
+type autoPrinter struct {
+	logger       xlog.Logger
+	textFpaths   []string
+	ebookFpaths  []string
+	officeFpaths []string
+	opts         AutoPrinterOptions
+}
+
+func (p autoPrinter) Print(destination string) error {
+	const op string = "printer.autoPrinter.Print"
+	logOptions(p.logger, p.opts)
+	dirPath := filepath.Dir(destination)
+	var fpaths []string
+	var waitTimeout float64
+	if len(p.textFpaths) > 0 {
+		dest := fmt.Sprintf("%s/%s.pdf", dirPath, xrand.Get())
+		if err := NewTextPrinter(p.logger, p.textFpaths, p.opts.TextOptions).Print(dest); err != nil {
+			return xerror.New(op, err)
+		}
+		fpaths = append(fpaths, dest)
+		waitTimeout = maxWaitTimeout(waitTimeout, p.opts.TextOptions.ChromePrinterOptions.WaitTimeout)
+	}
+	if len(p.ebookFpaths) > 0 {
+		dest := fmt.Sprintf("%s/%s.pdf", dirPath, xrand.Get())
+		if err := NewEbookPrinter(p.logger, p.ebookFpaths, p.opts.EbookOptions).Print(dest); err != nil {
+			return xerror.New(op, err)
+		}
+		fpaths = append(fpaths, dest)
+		waitTimeout = maxWaitTimeout(waitTimeout, p.opts.EbookOptions.WaitTimeout)
+	}
+	if len(p.officeFpaths) > 0 {
+		dest := fmt.Sprintf("%s/%s.pdf", dirPath, xrand.Get())
+		if err := NewOfficePrinter(p.logger, p.officeFpaths, p.opts.OfficeOptions).Print(dest); err != nil {
+			return xerror.New(op, err)
+		}
+		fpaths = append(fpaths, dest)
+		waitTimeout = maxWaitTimeout(waitTimeout, p.opts.OfficeOptions.WaitTimeout)
+	}
+	if len(fpaths) == 1 {
+		if err := os.Rename(fpaths[0], destination); err != nil {
+			return xerror.New(op, err)
+		}
+	} else {
+		ctx, cancel := xcontext.WithTimeout(p.logger, waitTimeout)
+		defer cancel()
+		m := mergePrinter{
+			logger: p.logger,
+			ctx:    ctx,
+			fpaths: fpaths,
+		}
+		if err := m.Print(destination); err != nil {
+			return xerror.New(op, err)
+		}
+	}
+	if err := applyPdfFormat(destination, p.opts.PdfFormat); err != nil {
+		return xerror.New(op, err)
+	}
+	if err := applyViewerPreferences(destination, p.opts.PdfViewerPreferences); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func maxWaitTimeout(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(autoPrinter))
+)
@@ -0,0 +1,25 @@
+package printer
+
+import "testing"
+
+func TestNewAutoPrinterDispatchesByExtension(t *testing.T) {
+	p := NewAutoPrinter(nil, []string{"a.txt", "b.epub", "c.docx"}, AutoPrinterOptions{}).(autoPrinter)
+	if len(p.textFpaths) != 1 || p.textFpaths[0] != "a.txt" {
+		t.Errorf("expected 'a.txt' to be routed to the TextPrinter, got %v", p.textFpaths)
+	}
+	if len(p.ebookFpaths) != 1 || p.ebookFpaths[0] != "b.epub" {
+		t.Errorf("expected 'b.epub' to be routed to the EbookPrinter, got %v", p.ebookFpaths)
+	}
+	if len(p.officeFpaths) != 1 || p.officeFpaths[0] != "c.docx" {
+		t.Errorf("expected 'c.docx' to be routed to the Office printer, got %v", p.officeFpaths)
+	}
+}
+
+func TestMaxWaitTimeout(t *testing.T) {
+	if got := maxWaitTimeout(3, 7); got != 7 {
+		t.Errorf("expected 7, got %v", got)
+	}
+	if got := maxWaitTimeout(7, 3); got != 7 {
+		t.Errorf("expected 7, got %v", got)
+	}
+}
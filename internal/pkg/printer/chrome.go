@@ -0,0 +1,125 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+type chromePrinter struct {
+	logger xlog.Logger
+	fpaths []string
+	opts   ChromePrinterOptions
+}
+
+// ChromePrinterOptions helps customizing the
+// Chrome Printer behaviour.
+type ChromePrinterOptions struct {
+	WaitTimeout float64
+	// PdfFormat, when set, requests an archival PDF/A
+	// conversion instead of a plain PDF. See PdfA1a, PdfA2b and
+	// PdfA3b. Chromium has no PDF/A export of its own, so this
+	// is applied as a post-processing step, see applyPdfFormat.
+	PdfFormat string
+	// PdfViewerPreferences lets callers control the initial
+	// presentation of the document in a PDF viewer.
+	PdfViewerPreferences PdfViewerPreferences
+}
+
+// DefaultChromePrinterOptions returns the default
+// Chrome Printer options.
+func DefaultChromePrinterOptions(config conf.Config) ChromePrinterOptions {
+	return ChromePrinterOptions{
+		WaitTimeout:          config.DefaultWaitTimeout(),
+		PdfFormat:            "",
+		PdfViewerPreferences: DefaultPdfViewerPreferences(),
+	}
+}
+
+// NewChromePrinter returns a Printer which is able to convert
+// HTML files to PDF using headless Chromium.
+func NewChromePrinter(logger xlog.Logger, fpaths []string, opts ChromePrinterOptions) Printer {
+	return chromePrinter{
+		logger: logger,
+		fpaths: fpaths,
+		opts:   opts,
+	}
+}
+
+func (p chromePrinter) Print(destination string) error {
+	const op string = "printer.chromePrinter.Print"
+	logOptions(p.logger, p.opts)
+	ctx, cancel := xcontext.WithTimeout(p.logger, p.opts.WaitTimeout)
+	defer cancel()
+	resolver := func() error {
+		// see https://github.com/thecodingmachine/gotenberg/issues/139.
+		sort.Strings(p.fpaths)
+		fpaths := make([]string, len(p.fpaths))
+		dirPath := filepath.Dir(destination)
+		for i, fpath := range p.fpaths {
+			baseFilename := xrand.Get()
+			tmpDest := fmt.Sprintf("%s/%d%s.pdf", dirPath, i, baseFilename)
+			p.logger.DebugOpf(op, "converting '%s' to PDF...", fpath)
+			if err := p.chromeToPdf(ctx, fpath, tmpDest); err != nil {
+				return err
+			}
+			p.logger.DebugOpf(op, "'%s.pdf' created", baseFilename)
+			fpaths[i] = tmpDest
+		}
+		if len(fpaths) == 1 {
+			p.logger.DebugOp(op, "only one PDF created, nothing to merge")
+			if err := os.Rename(fpaths[0], destination); err != nil {
+				return err
+			}
+		} else {
+			m := mergePrinter{
+				logger: p.logger,
+				ctx:    ctx,
+				fpaths: fpaths,
+			}
+			if err := m.Print(destination); err != nil {
+				return err
+			}
+		}
+		if err := applyPdfFormat(destination, p.opts.PdfFormat); err != nil {
+			return err
+		}
+		return applyViewerPreferences(destination, p.opts.PdfViewerPreferences)
+	}
+	if err := resolver(); err != nil {
+		return xcontext.MustHandleError(
+			ctx,
+			xerror.New(op, err),
+		)
+	}
+	return nil
+}
+
+func (p chromePrinter) chromeToPdf(ctx context.Context, fpath, destination string) error {
+	const op string = "printer.chromeToPdf"
+	args := []string{
+		"--headless",
+		"--disable-gpu",
+		"--no-sandbox",
+		fmt.Sprintf("--print-to-pdf=%s", destination),
+		fmt.Sprintf("file://%s", fpath),
+	}
+	if err := xexec.Run(ctx, p.logger, "google-chrome-stable", args...); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(chromePrinter))
+)
@@ -0,0 +1,144 @@
+package printer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/seccomp/libseccomp-golang"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"golang.org/x/sys/unix"
+)
+
+// seccompExecArg is the hidden argv[1] value that tells this
+// binary to load a seccomp profile and exec into the real
+// target instead of starting the Gotenberg server.
+const seccompExecArg = "__gotenberg_seccomp_exec__"
+
+// init runs MaybeSeccompExec before anything else in this
+// process, including before the Gotenberg server starts
+// listening, regardless of how main() is wired: package
+// initializers always run ahead of main(), and the printer
+// package is on the import path of every Gotenberg entrypoint.
+func init() {
+	MaybeSeccompExec()
+}
+
+// ProcessConfinement describes the sandboxing constraints
+// applied to the LibreOffice/unoconv subprocesses, since
+// Gotenberg feeds untrusted, macro-capable Office documents
+// (.doc, .xls, etc.) into them.
+type ProcessConfinement struct {
+	ApparmorProfile    string
+	SeccompProfilePath string
+	MaxAddressSpaceMB  int
+	MaxCPUSeconds      int
+	MaxOpenFiles       int
+}
+
+// DefaultProcessConfinement returns the confinement settings
+// taken from the running Gotenberg configuration.
+func DefaultProcessConfinement(config conf.Config) ProcessConfinement {
+	return ProcessConfinement{
+		ApparmorProfile:    config.LibreOfficeApparmorProfile(),
+		SeccompProfilePath: config.LibreOfficeSeccompProfile(),
+		MaxAddressSpaceMB:  config.LibreOfficeMaxAddressSpaceMB(),
+		MaxCPUSeconds:      config.LibreOfficeMaxCPUSeconds(),
+		MaxOpenFiles:       config.LibreOfficeMaxOpenFiles(),
+	}
+}
+
+// wrap prefixes name/args with whichever confinement wrappers
+// are configured, innermost first: a self-reexec seccomp
+// loader, then prlimit for resource limits, then aa-exec for
+// AppArmor, so the OS applies them from the inside out.
+func (c ProcessConfinement) wrap(name string, args []string) (string, []string) {
+	if c.SeccompProfilePath != "" {
+		args = append([]string{seccompExecArg, c.SeccompProfilePath, name}, args...)
+		name = "/proc/self/exe"
+		// once prlimit or aa-exec exec it instead of this process,
+		// /proc/self/exe would name them, so use our own path.
+		if c.ApparmorProfile != "" || c.MaxAddressSpaceMB > 0 || c.MaxCPUSeconds > 0 || c.MaxOpenFiles > 0 {
+			if exe, err := os.Executable(); err == nil {
+				name = exe
+			}
+		}
+	}
+	if c.MaxAddressSpaceMB > 0 || c.MaxCPUSeconds > 0 || c.MaxOpenFiles > 0 {
+		var limits []string
+		if c.MaxAddressSpaceMB > 0 {
+			limits = append(limits, fmt.Sprintf("--as=%d", c.MaxAddressSpaceMB*1024*1024))
+		}
+		if c.MaxCPUSeconds > 0 {
+			limits = append(limits, fmt.Sprintf("--cpu=%d", c.MaxCPUSeconds))
+		}
+		if c.MaxOpenFiles > 0 {
+			limits = append(limits, fmt.Sprintf("--nofile=%d", c.MaxOpenFiles))
+		}
+		args = append(append(limits, "--", name), args...)
+		name = "prlimit"
+	}
+	if c.ApparmorProfile != "" {
+		args = append([]string{"-p", c.ApparmorProfile, "--", name}, args...)
+		name = "aa-exec"
+	}
+	return name, args
+}
+
+// MaybeSeccompExec re-execs as the confined target process
+// when argv matches the self-reexec convention set up by
+// wrap. It never returns when it does.
+func MaybeSeccompExec() {
+	if len(os.Args) < 4 || os.Args[1] != seccompExecArg {
+		return
+	}
+	profilePath := os.Args[2]
+	target := os.Args[3]
+	targetArgs := os.Args[4:]
+	if err := applySeccompProfile(profilePath); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to apply seccomp profile '%s': %s\n", profilePath, err.Error())
+		os.Exit(1)
+	}
+	targetPath, err := exec.LookPath(target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to resolve '%s': %s\n", target, err.Error())
+		os.Exit(1)
+	}
+	if err := unix.Exec(targetPath, append([]string{target}, targetArgs...), os.Environ()); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to exec '%s': %s\n", target, err.Error())
+		os.Exit(1)
+	}
+}
+
+// applySeccompProfile sets PR_SET_NO_NEW_PRIVS then loads and
+// applies the JSON OCI-style seccomp profile at path.
+func applySeccompProfile(path string) error {
+	const op string = "printer.applySeccompProfile"
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("%s: set no_new_privs: %w", op, err)
+	}
+	profile, err := loadSeccompProfile(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	filter, err := seccomp.NewFilter(profile.defaultAction())
+	if err != nil {
+		return fmt.Errorf("%s: new filter: %w", op, err)
+	}
+	for _, rule := range profile.Syscalls {
+		action, err := rule.action()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		for _, name := range rule.Names {
+			id, err := seccomp.GetSyscallFromName(name)
+			if err != nil {
+				continue
+			}
+			if err := filter.AddRule(id, action); err != nil {
+				return fmt.Errorf("%s: add rule for '%s': %w", op, name, err)
+			}
+		}
+	}
+	return filter.Load()
+}
@@ -0,0 +1,51 @@
+package printer
+
+import "testing"
+
+func TestProcessConfinementWrapNoop(t *testing.T) {
+	var c ProcessConfinement
+	name, args := c.wrap("unoconv", []string{"--format", "pdf"})
+	if name != "unoconv" {
+		t.Errorf("expected name to be left untouched, got '%s'", name)
+	}
+	if len(args) != 2 || args[0] != "--format" || args[1] != "pdf" {
+		t.Errorf("expected args to be left untouched, got %v", args)
+	}
+}
+
+func TestProcessConfinementWrapApparmorAndLimits(t *testing.T) {
+	c := ProcessConfinement{
+		ApparmorProfile:   "gotenberg-soffice",
+		MaxAddressSpaceMB: 512,
+		MaxCPUSeconds:     30,
+		MaxOpenFiles:      64,
+	}
+	name, args := c.wrap("soffice", []string{"--headless"})
+	if name != "aa-exec" {
+		t.Fatalf("expected aa-exec as the outermost wrapper, got '%s'", name)
+	}
+	joined := args
+	if joined[0] != "-p" || joined[1] != "gotenberg-soffice" || joined[2] != "--" || joined[3] != "prlimit" {
+		t.Fatalf("expected aa-exec to wrap a prlimit invocation, got %v", args)
+	}
+}
+
+func TestProcessConfinementWrapSeccomp(t *testing.T) {
+	c := ProcessConfinement{SeccompProfilePath: "/etc/gotenberg/seccomp.json"}
+	name, args := c.wrap("unoconv", []string{"--format", "pdf"})
+	if name != "/proc/self/exe" {
+		t.Fatalf("expected a self-reexec, got '%s'", name)
+	}
+	if args[0] != seccompExecArg || args[1] != "/etc/gotenberg/seccomp.json" || args[2] != "unoconv" {
+		t.Fatalf("expected the self-reexec convention to be honored, got %v", args)
+	}
+}
+
+func TestSeccompAction(t *testing.T) {
+	if _, err := seccompAction("SCMP_ACT_ALLOW"); err != nil {
+		t.Errorf("expected SCMP_ACT_ALLOW to be supported: %s", err.Error())
+	}
+	if _, err := seccompAction("SCMP_ACT_NOTSUPPORTED"); err == nil {
+		t.Error("expected an unsupported action to return an error")
+	}
+}
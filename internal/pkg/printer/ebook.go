@@ -0,0 +1,176 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+// ebookExtensions lists the file extensions routed to the
+// EbookPrinter rather than the Office (unoconv) pipeline.
+var ebookExtensions = map[string]bool{
+	".epub": true,
+	".mobi": true,
+	".azw3": true,
+	".fb2":  true,
+	".umd":  true,
+	".lit":  true,
+}
+
+// IsEbookFilename tells if filename should be converted
+// by the EbookPrinter rather than the Office printer.
+func IsEbookFilename(filename string) bool {
+	return ebookExtensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+type ebookPrinter struct {
+	logger xlog.Logger
+	fpaths []string
+	opts   EbookPrinterOptions
+}
+
+// EbookPrinterOptions helps customizing the
+// Ebook Printer behaviour.
+type EbookPrinterOptions struct {
+	WaitTimeout      float64
+	PaperSize        string
+	PageMarginTop    int
+	PageMarginBottom int
+	PageMarginLeft   int
+	PageMarginRight  int
+	DefaultFontSize  int
+}
+
+// DefaultEbookPrinterOptions returns the default
+// Ebook Printer options.
+func DefaultEbookPrinterOptions(config conf.Config) EbookPrinterOptions {
+	return EbookPrinterOptions{
+		WaitTimeout:      config.DefaultWaitTimeout(),
+		PaperSize:        "",
+		PageMarginTop:    0,
+		PageMarginBottom: 0,
+		PageMarginLeft:   0,
+		PageMarginRight:  0,
+		DefaultFontSize:  0,
+	}
+}
+
+// NewEbookPrinter returns a Printer which is able
+// to convert ebook documents (EPUB, MOBI, AZW3, FB2,
+// UMD, LIT) to PDF.
+func NewEbookPrinter(logger xlog.Logger, fpaths []string, opts EbookPrinterOptions) Printer {
+	return ebookPrinter{
+		logger: logger,
+		fpaths: fpaths,
+		opts:   opts,
+	}
+}
+
+func (p ebookPrinter) Print(destination string) error {
+	const op string = "printer.ebookPrinter.Print"
+	logOptions(p.logger, p.opts)
+	ctx, cancel := xcontext.WithTimeout(p.logger, p.opts.WaitTimeout)
+	defer cancel()
+	resolver := func() error {
+		// see https://github.com/thecodingmachine/gotenberg/issues/139.
+		sort.Strings(p.fpaths)
+		fpaths := make([]string, len(p.fpaths))
+		dirPath := filepath.Dir(destination)
+		for i, fpath := range p.fpaths {
+			baseFilename := xrand.Get()
+			tmpDest := fmt.Sprintf("%s/%d%s.pdf", dirPath, i, baseFilename)
+			p.logger.DebugOpf(op, "converting '%s' to PDF...", fpath)
+			if err := p.ebookConvert(ctx, fpath, tmpDest); err != nil {
+				return err
+			}
+			p.logger.DebugOpf(op, "'%s.pdf' created", baseFilename)
+			fpaths[i] = tmpDest
+		}
+		if len(fpaths) == 1 {
+			p.logger.DebugOp(op, "only one PDF created, nothing to merge")
+			return os.Rename(fpaths[0], destination)
+		}
+		m := mergePrinter{
+			logger: p.logger,
+			ctx:    ctx,
+			fpaths: fpaths,
+		}
+		return m.Print(destination)
+	}
+	if err := resolver(); err != nil {
+		return xcontext.MustHandleError(
+			ctx,
+			xerror.New(op, err),
+		)
+	}
+	return nil
+}
+
+func (p ebookPrinter) ebookConvert(ctx context.Context, fpath, destination string) error {
+	const op string = "printer.ebookConvert"
+	resolver := func() error {
+		dirName := xrand.Get()
+		configDir := fmt.Sprintf("/tmp/%s", dirName)
+		if err := os.MkdirAll(configDir, 0700); err != nil {
+			return err
+		}
+		args := []string{
+			fpath,
+			destination,
+			"--output-profile", "default",
+		}
+		if p.opts.PaperSize != "" {
+			args = append(args, "--paper-size", p.opts.PaperSize)
+		}
+		if p.opts.PageMarginTop > 0 {
+			args = append(args, "--pdf-page-margin-top", fmt.Sprintf("%d", p.opts.PageMarginTop))
+		}
+		if p.opts.PageMarginBottom > 0 {
+			args = append(args, "--pdf-page-margin-bottom", fmt.Sprintf("%d", p.opts.PageMarginBottom))
+		}
+		if p.opts.PageMarginLeft > 0 {
+			args = append(args, "--pdf-page-margin-left", fmt.Sprintf("%d", p.opts.PageMarginLeft))
+		}
+		if p.opts.PageMarginRight > 0 {
+			args = append(args, "--pdf-page-margin-right", fmt.Sprintf("%d", p.opts.PageMarginRight))
+		}
+		if p.opts.DefaultFontSize > 0 {
+			args = append(args, "--pdf-default-font-size", fmt.Sprintf("%d", p.opts.DefaultFontSize))
+		}
+		// isolate this invocation's Calibre state in its own
+		// config directory, the ebook-convert equivalent of
+		// unoconv's --user-profile. Set on this subprocess's
+		// own Env, not the process-wide environment, so
+		// concurrent conversions never see each other's
+		// config directory.
+		cmd := exec.CommandContext(ctx, "ebook-convert", args...)
+		cmd.Env = append(os.Environ(), fmt.Sprintf("CALIBRE_CONFIG_DIRECTORY=%s", configDir))
+		output, err := cmd.CombinedOutput()
+		// always remove the Calibre config directory created above.
+		// see https://github.com/thecodingmachine/gotenberg/issues/192.
+		go cleanupUserProfile(p.logger, dirName)
+		if err != nil {
+			return fmt.Errorf("%s: %s", err.Error(), string(output))
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(ebookPrinter))
+)
@@ -0,0 +1,16 @@
+package printer
+
+import "testing"
+
+func TestIsEbookFilename(t *testing.T) {
+	for _, filename := range []string{"book.epub", "book.MOBI", "book.azw3", "book.fb2", "book.umd", "book.lit"} {
+		if !IsEbookFilename(filename) {
+			t.Errorf("expected '%s' to be recognized as an ebook", filename)
+		}
+	}
+	for _, filename := range []string{"document.docx", "notes.txt", "noextension"} {
+		if IsEbookFilename(filename) {
+			t.Errorf("expected '%s' not to be recognized as an ebook", filename)
+		}
+	}
+}
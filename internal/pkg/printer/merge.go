@@ -0,0 +1,31 @@
+package printer
+
+import (
+	"context"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+// mergePrinter merges one or more already-converted PDFs, in
+// the order given, into a single PDF at destination.
+type mergePrinter struct {
+	logger xlog.Logger
+	ctx    context.Context
+	fpaths []string
+}
+
+func (p mergePrinter) Print(destination string) error {
+	const op string = "printer.mergePrinter.Print"
+	args := append(append([]string{}, p.fpaths...), "cat", "output", destination)
+	if err := xexec.Run(p.ctx, p.logger, "pdftk", args...); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(mergePrinter))
+)
@@ -8,7 +8,6 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/phayes/freeport"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
@@ -32,18 +31,51 @@ type OfficePrinterOptions struct {
 	PaperHeight int
 	Landscape   bool
 	PageRanges  string
+	// PdfFormat, when set, requests an archival PDF/A
+	// conversion instead of a plain PDF. See PdfA1a,
+	// PdfA2b and PdfA3b.
+	//
+	// TODO: this tree has no handler/form-parsing package to
+	// wire a "pdfFormat" request form field into this option;
+	// until that package exists here, PdfFormat is reachable
+	// only by constructing OfficePrinterOptions directly.
+	PdfFormat string
+	// PdfViewerPreferences lets callers control the initial
+	// presentation of the document in a PDF viewer.
+	PdfViewerPreferences PdfViewerPreferences
+	// PoolSize is the number of warm soffice listeners kept
+	// around by the shared sofficePool.
+	PoolSize int
+	// WorkerMaxMemoryMB recycles a soffice worker once its
+	// resident memory grows past this budget. Zero disables
+	// the check.
+	WorkerMaxMemoryMB int
+	// WorkerMaxAge recycles a soffice worker once it has been
+	// running for longer than this many seconds. Zero disables
+	// the check.
+	WorkerMaxAge float64
+	// Confinement constrains the unoconv/soffice subprocesses,
+	// since Gotenberg feeds untrusted, macro-capable Office
+	// documents into them.
+	Confinement ProcessConfinement
 }
 
 // DefaultOfficePrinterOptions returns the default
 // Office Printer options.
 func DefaultOfficePrinterOptions(config conf.Config) OfficePrinterOptions {
 	return OfficePrinterOptions{
-		WaitTimeout: config.DefaultWaitTimeout(),
-		PaperFormat: "",
-		PaperWidth:  0,
-		PaperHeight: 0,
-		Landscape:   false,
-		PageRanges:  "",
+		WaitTimeout:          config.DefaultWaitTimeout(),
+		PaperFormat:          "",
+		PaperWidth:           0,
+		PaperHeight:          0,
+		Landscape:            false,
+		PageRanges:           "",
+		PdfFormat:            "",
+		PdfViewerPreferences: DefaultPdfViewerPreferences(),
+		PoolSize:             config.LibreOfficeWorkerCount(),
+		WorkerMaxMemoryMB:    config.LibreOfficeWorkerMaxMemoryMB(),
+		WorkerMaxAge:         config.LibreOfficeWorkerMaxAge(),
+		Confinement:          DefaultProcessConfinement(config),
 	}
 }
 
@@ -79,14 +111,25 @@ func (p officePrinter) Print(destination string) error {
 		}
 		if len(fpaths) == 1 {
 			p.logger.DebugOp(op, "only one PDF created, nothing to merge")
-			return os.Rename(fpaths[0], destination)
-		}
-		m := mergePrinter{
-			logger: p.logger,
-			ctx:    ctx,
-			fpaths: fpaths,
+			if err := os.Rename(fpaths[0], destination); err != nil {
+				return err
+			}
+		} else {
+			m := mergePrinter{
+				logger: p.logger,
+				ctx:    ctx,
+				fpaths: fpaths,
+			}
+			if err := m.Print(destination); err != nil {
+				return err
+			}
+			// the pdftk merge drops the OutputIntents/Metadata
+			// entries unoconv wrote, so add them back.
+			if err := applyPdfFormat(destination, p.opts.PdfFormat); err != nil {
+				return err
+			}
 		}
-		return m.Print(destination)
+		return applyViewerPreferences(destination, p.opts.PdfViewerPreferences)
 	}
 	if err := resolver(); err != nil {
 		return xcontext.MustHandleError(
@@ -100,16 +143,18 @@ func (p officePrinter) Print(destination string) error {
 func (p officePrinter) unoconv(ctx context.Context, fpath, destination string) error {
 	const op string = "printer.unoconv"
 	resolver := func() error {
-		dirName := xrand.Get()
-		port, err := freeport.GetFreePort()
+		pool, err := acquireSofficePool(p.logger, p.opts)
+		if err != nil {
+			return err
+		}
+		worker, err := pool.Acquire(ctx)
 		if err != nil {
 			return err
 		}
+		defer pool.Release(worker)
 		args := []string{
-			"--user-profile",
-			fmt.Sprintf("///tmp/%s", dirName),
-			"--port",
-			fmt.Sprintf("%d", port),
+			"--connection",
+			worker.connectionString(),
 			"--format",
 			"pdf",
 		}
@@ -125,11 +170,19 @@ func (p officePrinter) unoconv(ctx context.Context, fpath, destination string) e
 		if p.opts.PageRanges != "" {
 			args = append(args, "--export", fmt.Sprintf("PageRange=%s", p.opts.PageRanges))
 		}
+		if p.opts.PdfFormat != "" {
+			version, err := pdfFormatVersion(p.opts.PdfFormat)
+			if err != nil {
+				return err
+			}
+			args = append(args,
+				"--export", fmt.Sprintf("SelectPdfVersion=%d", version),
+				"--export", "UseTaggedPDF=true",
+			)
+		}
 		args = append(args, "--output", destination, fpath)
-		err = xexec.Run(ctx, p.logger, "unoconv", args...)
-		// always remove user profile folders created by LibreOffice.
-		// see https://github.com/thecodingmachine/gotenberg/issues/192.
-		go cleanupUserProfile(p.logger, dirName)
+		name, args := p.opts.Confinement.wrap("unoconv", args)
+		err = xexec.Run(ctx, p.logger, name, args...)
 		if err != nil {
 			// find a way to check it in the handlers?
 			if p.opts.PageRanges != "" && strings.Contains(err.Error(), "exit status 5") {
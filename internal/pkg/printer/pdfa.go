@@ -0,0 +1,105 @@
+package printer
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+// sRGBICCProfilePaths lists the well-known locations the
+// icc-profiles / colord packages install the sRGB ICC profile
+// at on Debian- and Alpine-based images, including Gotenberg's
+// own Docker image.
+var sRGBICCProfilePaths = []string{
+	"/usr/share/color/icc/colord/sRGB.icc",
+	"/usr/share/color/icc/sRGB.icc",
+	"/usr/share/ghostscript/iccprofiles/srgb.icc",
+}
+
+// applyPdfFormat embeds the objects required for minimal PDF/A
+// conformance, an sRGB OutputIntent and an XMP metadata stream,
+// into fpath. The Office printer gets this for free from
+// unoconv's SelectPdfVersion export filter; the merge and
+// Chrome pipelines have no native PDF/A writer, so this fills
+// the same gap for whichever PDF they hand back.
+func applyPdfFormat(fpath, format string) error {
+	const op string = "printer.applyPdfFormat"
+	if format == "" {
+		return nil
+	}
+	icc, err := readSRGBICCProfile()
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	data, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	rev, err := readLatestRevision(data)
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	iccNum := rev.size
+	intentNum := rev.size + 1
+	metaNum := rev.size + 2
+	xmp := xmpMetadata(format)
+	newCatalog := setCatalogEntries(rev.dict, map[string]string{
+		"OutputIntents": fmt.Sprintf("[%d 0 R]", intentNum),
+		"Metadata":      fmt.Sprintf("%d 0 R", metaNum),
+	})
+	objs := []pdfNewObject{
+		{num: rev.rootNum, gen: rev.rootGen, body: string(newCatalog)},
+		{num: iccNum, body: fmt.Sprintf("<< /N 3 /Alternate /DeviceRGB /Length %d >>\nstream\n%s\nendstream", len(icc), icc)},
+		{num: intentNum, body: fmt.Sprintf("<< /Type /OutputIntent /S /GTS_PDFA1 /OutputConditionIdentifier (sRGB IEC61966-2.1) /Info (sRGB IEC61966-2.1) /DestOutputProfile %d 0 R >>", iccNum)},
+		{num: metaNum, body: fmt.Sprintf("<< /Type /Metadata /Subtype /XML /Length %d >>\nstream\n%s\nendstream", len(xmp), xmp)},
+	}
+	out := appendIncrementalUpdateMulti(data, rev, objs)
+	if err := ioutil.WriteFile(fpath, out, 0600); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// readSRGBICCProfile returns the bytes of the first sRGB ICC
+// profile it finds among sRGBICCProfilePaths.
+func readSRGBICCProfile() ([]byte, error) {
+	const op string = "printer.readSRGBICCProfile"
+	for _, path := range sRGBICCProfilePaths {
+		data, err := ioutil.ReadFile(path)
+		if err == nil {
+			return data, nil
+		}
+	}
+	return nil, xerror.New(op, fmt.Errorf("no sRGB ICC profile found, looked in %v", sRGBICCProfilePaths))
+}
+
+// xmpMetadata returns a minimal XMP packet declaring format's
+// PDF/A part and conformance level, as the PDF/A specification
+// requires of a conforming file's Metadata stream.
+func xmpMetadata(format string) string {
+	part, conformance := pdfaXMPIdentifiers(format)
+	return fmt.Sprintf(xmpTemplate, part, conformance)
+}
+
+func pdfaXMPIdentifiers(format string) (part, conformance string) {
+	switch format {
+	case PdfA1a:
+		return "1", "A"
+	case PdfA3b:
+		return "3", "B"
+	default:
+		return "2", "B"
+	}
+}
+
+const xmpTemplate = `<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">
+<pdfaid:part>%s</pdfaid:part>
+<pdfaid:conformance>%s</pdfaid:conformance>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`
@@ -0,0 +1,115 @@
+package printer
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPdfaXMPIdentifiers(t *testing.T) {
+	cases := map[string][2]string{
+		PdfA1a: {"1", "A"},
+		PdfA2b: {"2", "B"},
+		PdfA3b: {"3", "B"},
+	}
+	for format, want := range cases {
+		part, conformance := pdfaXMPIdentifiers(format)
+		if part != want[0] || conformance != want[1] {
+			t.Errorf("%s: expected part=%s conformance=%s, got part=%s conformance=%s", format, want[0], want[1], part, conformance)
+		}
+	}
+}
+
+func TestXmpMetadataDeclaresConformance(t *testing.T) {
+	xmp := xmpMetadata(PdfA2b)
+	if !strings.Contains(xmp, "<pdfaid:part>2</pdfaid:part>") || !strings.Contains(xmp, "<pdfaid:conformance>B</pdfaid:conformance>") {
+		t.Errorf("expected XMP packet to declare PDF/A-2b, got %q", xmp)
+	}
+}
+
+func TestReadSRGBICCProfileErrorsWhenNoneFound(t *testing.T) {
+	original := sRGBICCProfilePaths
+	defer func() { sRGBICCProfilePaths = original }()
+	sRGBICCProfilePaths = []string{"/nonexistent/sRGB.icc"}
+	if _, err := readSRGBICCProfile(); err == nil {
+		t.Error("expected an error when no sRGB ICC profile can be found")
+	}
+}
+
+func TestApplyPdfFormatNoOp(t *testing.T) {
+	if err := applyPdfFormat("/nonexistent/file.pdf", ""); err != nil {
+		t.Errorf("expected a no-op when format is empty, got %s", err.Error())
+	}
+}
+
+// withFakeSRGBICCProfile points sRGBICCProfilePaths at a throwaway
+// file for the duration of the test, so applyPdfFormat doesn't
+// depend on a real ICC profile being installed on the test host.
+func withFakeSRGBICCProfile(t *testing.T) {
+	t.Helper()
+	f, err := ioutil.TempFile("", "gotenberg-test-*.icc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("fake icc profile bytes"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	original := sRGBICCProfilePaths
+	sRGBICCProfilePaths = []string{f.Name()}
+	t.Cleanup(func() {
+		os.Remove(f.Name())
+		sRGBICCProfilePaths = original
+	})
+}
+
+// TestApplyPdfFormatThenApplyViewerPreferences guards against a
+// regression where applyViewerPreferences, run right after
+// applyPdfFormat on the same file (exactly what chromePrinter.Print
+// does), rebuilds its incremental update from the stale,
+// pre-PDF/A catalog and silently drops the just-added
+// /OutputIntents and /Metadata entries.
+func TestApplyPdfFormatThenApplyViewerPreferences(t *testing.T) {
+	withFakeSRGBICCProfile(t)
+	f, err := ioutil.TempFile("", "gotenberg-test-*.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(minimalClassicPdf); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if err := applyPdfFormat(f.Name(), PdfA2b); err != nil {
+		t.Fatalf("applyPdfFormat returned an error: %s", err.Error())
+	}
+	if err := applyViewerPreferences(f.Name(), PdfViewerPreferences{PageLayout: PageLayoutTwoColumnLeft}); err != nil {
+		t.Fatalf("applyViewerPreferences returned an error: %s", err.Error())
+	}
+
+	out, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// read back the *latest* catalog revision specifically,
+	// rather than grepping the whole file: every earlier
+	// revision's bytes are still physically present too, so a
+	// plain substring check on the whole file would pass even
+	// if readLatestRevision regressed to picking a stale one.
+	rev, err := readLatestRevision(out)
+	if err != nil {
+		t.Fatalf("readLatestRevision returned an error: %s", err.Error())
+	}
+	dict := string(rev.dict)
+	if !strings.Contains(dict, "/OutputIntents") {
+		t.Errorf("expected the latest catalog revision to still carry /OutputIntents from applyPdfFormat, got:\n%s", dict)
+	}
+	if !strings.Contains(dict, "/Metadata") {
+		t.Errorf("expected the latest catalog revision to still carry /Metadata from applyPdfFormat, got:\n%s", dict)
+	}
+	if !strings.Contains(dict, "/PageLayout /TwoColumnLeft") {
+		t.Errorf("expected the latest catalog revision to carry /PageLayout from applyViewerPreferences, got:\n%s", dict)
+	}
+}
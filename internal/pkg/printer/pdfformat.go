@@ -0,0 +1,35 @@
+package printer
+
+import (
+	"fmt"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+// Supported archival PDF formats, as understood by the
+// PdfFormat option of the printers below.
+const (
+	PdfA1a string = "PDF/A-1a"
+	PdfA2b string = "PDF/A-2b"
+	PdfA3b string = "PDF/A-3b"
+)
+
+// pdfFormatVersion maps a PdfFormat value to the LibreOffice
+// SelectPdfVersion export filter value.
+func pdfFormatVersion(format string) (int, error) {
+	const op string = "printer.pdfFormatVersion"
+	switch format {
+	case PdfA1a:
+		return 1, nil
+	case PdfA2b:
+		return 2, nil
+	case PdfA3b:
+		return 3, nil
+	default:
+		return 0, xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' is not a valid PDF format", format),
+			nil,
+		)
+	}
+}
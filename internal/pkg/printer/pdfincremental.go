@@ -0,0 +1,171 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+var (
+	reStartXref = regexp.MustCompile(`startxref\s+(\d+)`)
+	reTrailer   = regexp.MustCompile(`(?s)trailer\s*(<<.*?>>)\s*startxref`)
+	reRoot      = regexp.MustCompile(`/Root\s+(\d+)\s+(\d+)\s+R`)
+	reSize      = regexp.MustCompile(`/Size\s+(\d+)`)
+)
+
+// pdfRevision describes what an incremental update needs to
+// know about the newest revision of a PDF file: where its
+// document catalog object lives and what its trailer looked
+// like.
+type pdfRevision struct {
+	rootNum, rootGen int
+	size             int
+	startXref        int64
+	dict             []byte // the catalog's "<< ... >>", as found
+}
+
+// readLatestRevision locates the newest trailer and document
+// catalog object of a classic cross-reference-table PDF. It
+// returns an error, rather than guessing at their layout, for
+// PDFs built around cross-reference streams (PDF 1.5+) since
+// those have no "trailer" keyword to anchor on.
+func readLatestRevision(data []byte) (*pdfRevision, error) {
+	const op string = "printer.readLatestRevision"
+	startMatches := reStartXref.FindAllSubmatch(data, -1)
+	if len(startMatches) == 0 {
+		return nil, xerror.New(op, fmt.Errorf("no startxref found"))
+	}
+	last := startMatches[len(startMatches)-1]
+	startXref, err := strconv.ParseInt(string(last[1]), 10, 64)
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	trailerMatches := reTrailer.FindAllSubmatch(data, -1)
+	if len(trailerMatches) == 0 {
+		return nil, xerror.New(op, fmt.Errorf("no classic trailer found, PDF likely uses cross-reference streams"))
+	}
+	trailer := trailerMatches[len(trailerMatches)-1][1]
+	rootMatch := reRoot.FindSubmatch(trailer)
+	if rootMatch == nil {
+		return nil, xerror.New(op, fmt.Errorf("trailer has no /Root entry"))
+	}
+	rootNum, _ := strconv.Atoi(string(rootMatch[1]))
+	rootGen, _ := strconv.Atoi(string(rootMatch[2]))
+	size := 0
+	if sizeMatch := reSize.FindSubmatch(trailer); sizeMatch != nil {
+		size, _ = strconv.Atoi(string(sizeMatch[1]))
+	}
+	objRe := regexp.MustCompile(fmt.Sprintf(`(?s)\b%d\s+%d\s+obj\s*(<<.*?>>)\s*endobj`, rootNum, rootGen))
+	// an incremental update appends a new revision of the
+	// catalog object after all earlier ones without removing
+	// them, so the first match in the file is typically the
+	// oldest revision, not the latest. Take the last one.
+	locs := objRe.FindAllSubmatch(data, -1)
+	if locs == nil {
+		return nil, xerror.New(op, fmt.Errorf("catalog object %d %d not found", rootNum, rootGen))
+	}
+	loc := locs[len(locs)-1]
+	return &pdfRevision{
+		rootNum:   rootNum,
+		rootGen:   rootGen,
+		size:      size,
+		startXref: startXref,
+		dict:      loc[1],
+	}, nil
+}
+
+// setCatalogEntries returns dict with each /name entry in
+// entries inserted, replacing any existing entry of the same
+// name. Values must already be serialized PDF objects (e.g.
+// "/SinglePage", "[1 2 3]").
+func setCatalogEntries(dict []byte, entries map[string]string) []byte {
+	body := dict[2 : len(dict)-2]
+	for name := range entries {
+		re := regexp.MustCompile(`/` + name + `\s*(/[^/>\s]+|\([^)]*\)|\[[^\]]*\]|<[0-9A-Fa-f]*>|\d+\s+\d+\s+R\b|\d+(\.\d+)?)`)
+		body = re.ReplaceAll(body, nil)
+	}
+	var extra bytes.Buffer
+	for name, value := range entries {
+		fmt.Fprintf(&extra, "/%s %s", name, value)
+	}
+	out := make([]byte, 0, len(body)+extra.Len()+4)
+	out = append(out, '<', '<')
+	out = append(out, body...)
+	out = append(out, extra.Bytes()...)
+	out = append(out, '>', '>')
+	return out
+}
+
+// appendIncrementalUpdate appends a new revision of the
+// catalog object to data: the object itself, a single-entry
+// xref section pointing at it, and a trailer that chains back
+// to the file's previous trailer via /Prev. Every other object
+// in data is left untouched, so no existing xref offset ever
+// goes stale.
+func appendIncrementalUpdate(data []byte, rev *pdfRevision, newDict []byte) []byte {
+	var out bytes.Buffer
+	out.Write(data)
+	if out.Len() > 0 && out.Bytes()[out.Len()-1] != '\n' {
+		out.WriteByte('\n')
+	}
+	objOffset := out.Len()
+	fmt.Fprintf(&out, "%d %d obj\n%s\nendobj\n", rev.rootNum, rev.rootGen, newDict)
+	xrefOffset := out.Len()
+	fmt.Fprintf(&out, "xref\n%d 1\n%010d %05d n \n", rev.rootNum, objOffset, rev.rootGen)
+	size := rev.size
+	if rev.rootNum+1 > size {
+		size = rev.rootNum + 1
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d %d R /Prev %d >>\nstartxref\n%d\n%%%%EOF\n",
+		size, rev.rootNum, rev.rootGen, rev.startXref, xrefOffset)
+	return out.Bytes()
+}
+
+// pdfNewObject is a single object to append during a multi-
+// object incremental update: num/gen identify it, body is
+// everything between "obj" and "endobj".
+type pdfNewObject struct {
+	num, gen int
+	body     string
+}
+
+// appendIncrementalUpdateMulti is appendIncrementalUpdate
+// generalized to a whole set of new objects in one revision,
+// e.g. a new catalog plus the OutputIntent and Metadata objects
+// it now references. Every other object in data is left
+// untouched, so no existing xref offset ever goes stale.
+func appendIncrementalUpdateMulti(data []byte, rev *pdfRevision, objs []pdfNewObject) []byte {
+	var out bytes.Buffer
+	out.Write(data)
+	if out.Len() > 0 && out.Bytes()[out.Len()-1] != '\n' {
+		out.WriteByte('\n')
+	}
+	offsets := make(map[int]int, len(objs))
+	maxNum := rev.rootNum
+	for _, o := range objs {
+		offsets[o.num] = out.Len()
+		fmt.Fprintf(&out, "%d %d obj\n%s\nendobj\n", o.num, o.gen, o.body)
+		if o.num > maxNum {
+			maxNum = o.num
+		}
+	}
+	sorted := make([]pdfNewObject, len(objs))
+	copy(sorted, objs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].num < sorted[j].num })
+	xrefOffset := out.Len()
+	out.WriteString("xref\n")
+	for _, o := range sorted {
+		fmt.Fprintf(&out, "%d 1\n%010d %05d n \n", o.num, offsets[o.num], o.gen)
+	}
+	size := rev.size
+	if maxNum+1 > size {
+		size = maxNum + 1
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d %d R /Prev %d >>\nstartxref\n%d\n%%%%EOF\n",
+		size, rev.rootNum, rev.rootGen, rev.startXref, xrefOffset)
+	return out.Bytes()
+}
@@ -0,0 +1,275 @@
+package printer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+// sofficeWorker is a long-lived, pre-warmed LibreOffice
+// listener instance with its own persistent user profile.
+type sofficeWorker struct {
+	port       int
+	profileDir string
+	cmd        *exec.Cmd
+	startedAt  time.Time
+}
+
+// connectionString returns the unoconv --connection value
+// used to reach this worker's soffice listener.
+func (w *sofficeWorker) connectionString() string {
+	return fmt.Sprintf("socket,host=127.0.0.1,port=%d;urp;StarOffice.ComponentContext", w.port)
+}
+
+// kill terminates the worker's soffice process and removes
+// its user profile directory.
+func (w *sofficeWorker) kill(logger xlog.Logger) {
+	const op string = "printer.sofficeWorker.kill"
+	if err := w.cmd.Process.Kill(); err != nil {
+		logger.ErrorOpf(op, "failed to kill soffice worker on port %d: %s", w.port, err.Error())
+	}
+	// reap the process so it does not linger as a zombie.
+	go func() {
+		_ = w.cmd.Wait()
+	}()
+	// always remove user profile folders created by LibreOffice.
+	// see https://github.com/thecodingmachine/gotenberg/issues/192.
+	go cleanupUserProfile(logger, filepath.Base(w.profileDir))
+}
+
+// sofficePool keeps a fixed number of warm sofficeWorker
+// instances around so that officePrinter.unoconv does not
+// have to pay LibreOffice's cold start cost on every file.
+type sofficePool struct {
+	logger      xlog.Logger
+	maxMemoryMB int
+	maxAge      time.Duration
+	confinement ProcessConfinement
+	workers     chan *sofficeWorker
+}
+
+var (
+	sofficePoolMu     sync.Mutex
+	sharedSofficePool *sofficePool
+)
+
+// acquireSofficePool lazily starts the shared pool of
+// LibreOffice listeners sized and configured by opts, then
+// returns it. Unlike a sync.Once, a failed attempt is not
+// cached: a transient failure (e.g. a free port race, or one
+// soffice listener that doesn't come up in time) must not
+// permanently poison every future Office conversion for the
+// life of the process, so the next caller retries from scratch.
+func acquireSofficePool(logger xlog.Logger, opts OfficePrinterOptions) (*sofficePool, error) {
+	sofficePoolMu.Lock()
+	defer sofficePoolMu.Unlock()
+	if sharedSofficePool != nil {
+		return sharedSofficePool, nil
+	}
+	sp, err := newSofficePool(logger, opts)
+	if err != nil {
+		return nil, err
+	}
+	sharedSofficePool = sp
+	return sharedSofficePool, nil
+}
+
+func newSofficePool(logger xlog.Logger, opts OfficePrinterOptions) (*sofficePool, error) {
+	const op string = "printer.newSofficePool"
+	size := opts.PoolSize
+	if size <= 0 {
+		size = 1
+	}
+	sp := &sofficePool{
+		logger:      logger,
+		maxMemoryMB: opts.WorkerMaxMemoryMB,
+		maxAge:      time.Duration(opts.WorkerMaxAge * float64(time.Second)),
+		confinement: opts.Confinement,
+		workers:     make(chan *sofficeWorker, size),
+	}
+	spawned := make([]*sofficeWorker, 0, size)
+	for i := 0; i < size; i++ {
+		w, err := sp.spawn()
+		if err != nil {
+			// don't leak the workers already started before this
+			// one failed; nothing else will ever kill them once
+			// newSofficePool returns an error.
+			for _, spawnedWorker := range spawned {
+				spawnedWorker.kill(logger)
+			}
+			return nil, xerror.New(op, err)
+		}
+		spawned = append(spawned, w)
+		sp.workers <- w
+	}
+	return sp, nil
+}
+
+func (sp *sofficePool) spawn() (*sofficeWorker, error) {
+	const op string = "printer.sofficePool.spawn"
+	port, err := freeport.GetFreePort()
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	profileDir := fmt.Sprintf("/tmp/%s", xrand.Get())
+	args := []string{
+		"--headless",
+		"--invisible",
+		"--nocrashreport",
+		"--nodefault",
+		"--nofirststartwizard",
+		"--nologo",
+		"--norestore",
+		fmt.Sprintf("--accept=socket,host=127.0.0.1,port=%d;urp;", port),
+		fmt.Sprintf("--user-profile=file:///%s", profileDir),
+	}
+	name, args := sp.confinement.wrap("soffice", args)
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return nil, xerror.New(op, err)
+	}
+	if err := waitForListener(port, sofficeListenerReadyTimeout); err != nil {
+		_ = cmd.Process.Kill()
+		return nil, xerror.New(op, err)
+	}
+	sp.logger.DebugOpf(op, "spawned soffice worker on port %d", port)
+	return &sofficeWorker{
+		port:       port,
+		profileDir: profileDir,
+		cmd:        cmd,
+		startedAt:  time.Now(),
+	}, nil
+}
+
+// sofficeListenerReadyTimeout bounds how long spawn waits for
+// a freshly started soffice process to open its UNO socket.
+const sofficeListenerReadyTimeout = 30 * time.Second
+
+// waitForListener polls port until something accepts TCP
+// connections on it or timeout elapses. soffice takes real
+// wall-clock time to start listening after --accept is given,
+// so workers must not be handed out before this succeeds.
+func waitForListener(port int, timeout time.Duration) error {
+	const op string = "printer.waitForListener"
+	deadline := time.Now().Add(timeout)
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, 200*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return xerror.New(op, fmt.Errorf("soffice listener on port %d did not become ready within %s", port, timeout))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// Acquire checks out a warm worker, blocking until one
+// becomes available or ctx is done.
+func (sp *sofficePool) Acquire(ctx context.Context) (*sofficeWorker, error) {
+	const op string = "printer.sofficePool.Acquire"
+	select {
+	case w := <-sp.workers:
+		return w, nil
+	case <-ctx.Done():
+		return nil, xerror.New(op, ctx.Err())
+	}
+}
+
+// Release returns w to the pool, recycling it first if it
+// has exceeded its age or memory budget. If the recycled
+// worker fails to respawn, the slot is dropped rather than
+// handed back out dead; respawnWithBackoff keeps retrying in
+// the background until pool capacity is restored.
+func (sp *sofficePool) Release(w *sofficeWorker) {
+	const op string = "printer.sofficePool.Release"
+	if !sp.expired(w) {
+		sp.workers <- w
+		return
+	}
+	sp.logger.DebugOpf(op, "recycling soffice worker on port %d", w.port)
+	w.kill(sp.logger)
+	fresh, err := sp.spawn()
+	if err != nil {
+		sp.logger.ErrorOpf(op, "failed to respawn soffice worker, retrying in background: %s", err.Error())
+		go sp.respawnWithBackoff()
+		return
+	}
+	sp.workers <- fresh
+}
+
+// respawnWithBackoff keeps retrying to start a replacement
+// soffice worker, with exponential backoff, until it succeeds
+// and the slot is returned to the pool.
+func (sp *sofficePool) respawnWithBackoff() {
+	const op string = "printer.sofficePool.respawnWithBackoff"
+	backoff := time.Second
+	for {
+		w, err := sp.spawn()
+		if err == nil {
+			sp.workers <- w
+			return
+		}
+		sp.logger.ErrorOpf(op, "failed to respawn soffice worker: %s", err.Error())
+		time.Sleep(backoff)
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+func (sp *sofficePool) expired(w *sofficeWorker) bool {
+	if sp.maxAge > 0 && time.Since(w.startedAt) > sp.maxAge {
+		return true
+	}
+	if sp.maxMemoryMB > 0 {
+		rss, err := processResidentMemoryMB(w.cmd.Process.Pid)
+		if err == nil && rss > sp.maxMemoryMB {
+			return true
+		}
+	}
+	return false
+}
+
+// processResidentMemoryMB reads the resident set size of pid
+// from /proc, in megabytes.
+func processResidentMemoryMB(pid int) (int, error) {
+	const op string = "printer.processResidentMemoryMB"
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, xerror.New(op, err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "VmRSS:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			break
+		}
+		kb, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0, xerror.New(op, err)
+		}
+		return kb / 1024, nil
+	}
+	return 0, xerror.New(op, fmt.Errorf("VmRSS not found for pid %d", pid))
+}
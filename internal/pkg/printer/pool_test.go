@@ -0,0 +1,47 @@
+package printer
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSofficePoolExpiredByAge(t *testing.T) {
+	sp := &sofficePool{maxAge: 10 * time.Millisecond}
+	w := &sofficeWorker{startedAt: time.Now().Add(-time.Second)}
+	if !sp.expired(w) {
+		t.Error("expected a worker older than maxAge to be expired")
+	}
+}
+
+func TestSofficePoolNotExpiredWhenDisabled(t *testing.T) {
+	sp := &sofficePool{}
+	w := &sofficeWorker{startedAt: time.Now().Add(-time.Hour)}
+	if sp.expired(w) {
+		t.Error("expected no expiry when maxAge and maxMemoryMB are both zero")
+	}
+}
+
+func TestWaitForListenerSucceeds(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+	if err := waitForListener(port, time.Second); err != nil {
+		t.Errorf("expected waitForListener to succeed against an open port: %s", err.Error())
+	}
+}
+
+func TestWaitForListenerTimesOut(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	if err := waitForListener(port, 300*time.Millisecond); err == nil {
+		t.Error("expected waitForListener to time out against a closed port")
+	}
+}
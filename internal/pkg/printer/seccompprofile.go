@@ -0,0 +1,61 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/seccomp/libseccomp-golang"
+)
+
+// seccompProfile is the subset of the OCI runtime-spec
+// seccomp profile format (as used by Docker/containerd)
+// that Gotenberg understands.
+type seccompProfile struct {
+	DefaultAction string           `json:"defaultAction"`
+	Syscalls      []seccompSyscall `json:"syscalls"`
+}
+
+type seccompSyscall struct {
+	Names  []string `json:"names"`
+	Action string   `json:"action"`
+}
+
+func loadSeccompProfile(path string) (*seccompProfile, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var profile seccompProfile
+	if err := json.Unmarshal(raw, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (p *seccompProfile) defaultAction() seccomp.ScmpAction {
+	action, err := seccompAction(p.DefaultAction)
+	if err != nil {
+		return seccomp.ActErrno
+	}
+	return action
+}
+
+func (s seccompSyscall) action() (seccomp.ScmpAction, error) {
+	return seccompAction(s.Action)
+}
+
+func seccompAction(name string) (seccomp.ScmpAction, error) {
+	switch name {
+	case "SCMP_ACT_ALLOW":
+		return seccomp.ActAllow, nil
+	case "SCMP_ACT_ERRNO":
+		return seccomp.ActErrno, nil
+	case "SCMP_ACT_KILL":
+		return seccomp.ActKill, nil
+	case "SCMP_ACT_TRAP":
+		return seccomp.ActTrap, nil
+	default:
+		return seccomp.ActErrno, fmt.Errorf("'%s' is not a supported seccomp action", name)
+	}
+}
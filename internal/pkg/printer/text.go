@@ -0,0 +1,223 @@
+package printer
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+// textExtensions lists the file extensions routed to the
+// TextPrinter rather than the Office (unoconv) pipeline.
+var textExtensions = map[string]bool{
+	".txt":  true,
+	".md":   true,
+	".log":  true,
+	".go":   true,
+	".py":   true,
+	".js":   true,
+	".java": true,
+	".c":    true,
+	".h":    true,
+	".cpp":  true,
+	".rb":   true,
+	".sh":   true,
+}
+
+// IsTextFilename tells if filename should be converted
+// by the TextPrinter rather than the Office printer.
+func IsTextFilename(filename string) bool {
+	return textExtensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+type textPrinter struct {
+	logger xlog.Logger
+	fpaths []string
+	opts   TextPrinterOptions
+}
+
+// TextPrinterOptions helps customizing the
+// Text Printer behaviour.
+type TextPrinterOptions struct {
+	ChromePrinterOptions ChromePrinterOptions
+	FontFamily           string
+	FontSize             int
+	LineNumbers          bool
+	WrapWidth            int
+	TabWidth             int
+	SyntaxHighlighting   bool
+}
+
+// DefaultTextPrinterOptions returns the default
+// Text Printer options.
+func DefaultTextPrinterOptions(config conf.Config) TextPrinterOptions {
+	return TextPrinterOptions{
+		ChromePrinterOptions: DefaultChromePrinterOptions(config),
+		FontFamily:           "monospace",
+		FontSize:             12,
+		LineNumbers:          false,
+		WrapWidth:            0,
+		TabWidth:             4,
+		SyntaxHighlighting:   true,
+	}
+}
+
+// NewTextPrinter returns a Printer which is able to convert
+// plain text and source files to PDF.
+func NewTextPrinter(logger xlog.Logger, fpaths []string, opts TextPrinterOptions) Printer {
+	return textPrinter{
+		logger: logger,
+		fpaths: fpaths,
+		opts:   opts,
+	}
+}
+
+func (p textPrinter) Print(destination string) error {
+	const op string = "printer.textPrinter.Print"
+	logOptions(p.logger, p.opts)
+	fpaths := make([]string, len(p.fpaths))
+	for i, fpath := range p.fpaths {
+		htmlPath, err := p.toHTML(fpath)
+		if err != nil {
+			return xerror.New(op, err)
+		}
+		fpaths[i] = htmlPath
+	}
+	return NewChromePrinter(p.logger, fpaths, p.opts.ChromePrinterOptions).Print(destination)
+}
+
+// toHTML reads fpath, detects its character encoding, decodes it
+// to UTF-8, optionally runs it through Chroma for syntax
+// highlighting and wraps the result in a monospace HTML template.
+func (p textPrinter) toHTML(fpath string) (string, error) {
+	const op string = "printer.textPrinter.toHTML"
+	raw, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return "", xerror.New(op, err)
+	}
+	text, err := decodeText(raw)
+	if err != nil {
+		return "", xerror.New(op, err)
+	}
+	var body string
+	if p.opts.SyntaxHighlighting {
+		body, err = highlight(fpath, text, p.opts.LineNumbers)
+		if err != nil {
+			return "", xerror.New(op, err)
+		}
+	} else {
+		body = fmt.Sprintf("<pre>%s</pre>", renderPlainText(text, p.opts.LineNumbers))
+	}
+	htmlPath := fmt.Sprintf("%s.html", fpath)
+	content := fmt.Sprintf(textTemplate, p.cssOptions(), body)
+	if err := ioutil.WriteFile(htmlPath, []byte(content), 0600); err != nil {
+		return "", xerror.New(op, err)
+	}
+	return htmlPath, nil
+}
+
+func (p textPrinter) cssOptions() string {
+	wrap := "pre"
+	if p.opts.WrapWidth > 0 {
+		wrap = "pre-wrap"
+	}
+	base := fmt.Sprintf(
+		`font-family: %s, monospace; font-size: %dpt; white-space: %s; tab-size: %d;`,
+		p.opts.FontFamily, p.opts.FontSize, wrap, p.opts.TabWidth,
+	)
+	if !p.opts.LineNumbers {
+		return base
+	}
+	return base + " " + lineNumberingCSS
+}
+
+// lineNumberingCSS numbers each .line span via a CSS counter,
+// incremented once per line and rendered in a ::before gutter.
+const lineNumberingCSS = `pre { counter-reset: line; } .line { counter-increment: line; } .line::before { content: counter(line); display: inline-block; width: 3em; margin-right: 1em; text-align: right; color: #999; }`
+
+// renderPlainText HTML-escapes text and, when lineNumbers is
+// set, wraps each line in a .line span so lineNumberingCSS can
+// number it.
+func renderPlainText(text string, lineNumbers bool) string {
+	if !lineNumbers {
+		return html.EscapeString(text)
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = fmt.Sprintf(`<span class="line">%s</span>`, html.EscapeString(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// decodeText auto-detects whether raw is UTF-8, GBK or
+// Shift-JIS, and returns its UTF-8 decoded content.
+func decodeText(raw []byte) (string, error) {
+	const op string = "printer.decodeText"
+	if utf8.Valid(raw) {
+		return string(raw), nil
+	}
+	candidates := []encoding.Encoding{
+		simplifiedchinese.GBK,
+		japanese.ShiftJIS,
+	}
+	for _, enc := range candidates {
+		decoded, err := enc.NewDecoder().Bytes(raw)
+		// decoders replace invalid sequences with U+FFFD rather
+		// than failing, so a clean decode is one without any.
+		if err == nil && !strings.ContainsRune(string(decoded), utf8.RuneError) {
+			return string(decoded), nil
+		}
+	}
+	return "", xerror.New(op, fmt.Errorf("unable to detect character encoding"))
+}
+
+// highlight renders source into syntax-highlighted HTML using
+// Chroma, picking a lexer from fpath's extension.
+func highlight(fpath, source string, lineNumbers bool) (string, error) {
+	const op string = "printer.highlight"
+	lexer := lexers.Match(fpath)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	style := styles.Get("github")
+	if style == nil {
+		style = styles.Fallback
+	}
+	formatter := chromahtml.New(chromahtml.WithLineNumbers(lineNumbers))
+	iterator, err := lexer.Tokenise(nil, source)
+	if err != nil {
+		return "", xerror.New(op, err)
+	}
+	var buf strings.Builder
+	if err := formatter.Format(&buf, style, iterator); err != nil {
+		return "", xerror.New(op, err)
+	}
+	return buf.String(), nil
+}
+
+const textTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<style>body { margin: 0; } pre, code { %s }</style>
+</head>
+<body>%s</body>
+</html>`
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(textPrinter))
+)
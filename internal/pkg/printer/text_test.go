@@ -0,0 +1,45 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsTextFilename(t *testing.T) {
+	for _, filename := range []string{"a.txt", "b.MD", "main.go", "script.sh"} {
+		if !IsTextFilename(filename) {
+			t.Errorf("expected '%s' to be recognized as text", filename)
+		}
+	}
+	for _, filename := range []string{"doc.docx", "book.epub", "noextension"} {
+		if IsTextFilename(filename) {
+			t.Errorf("expected '%s' not to be recognized as text", filename)
+		}
+	}
+}
+
+func TestRenderPlainTextNoLineNumbers(t *testing.T) {
+	got := renderPlainText("a\nb", false)
+	if got != "a\nb" {
+		t.Errorf("expected plain escaped text without spans, got %q", got)
+	}
+}
+
+func TestRenderPlainTextWithLineNumbers(t *testing.T) {
+	got := renderPlainText("a\nb", true)
+	want := `<span class="line">a</span>` + "\n" + `<span class="line">b</span>`
+	if got != want {
+		t.Errorf("expected each line wrapped in a .line span, got %q", got)
+	}
+}
+
+func TestCssOptionsIncludesLineNumberingOnlyWhenEnabled(t *testing.T) {
+	p := textPrinter{opts: TextPrinterOptions{FontFamily: "monospace", FontSize: 12, TabWidth: 4}}
+	if got := p.cssOptions(); strings.Contains(got, "counter-increment") {
+		t.Errorf("expected no line-numbering CSS when disabled, got %q", got)
+	}
+	p.opts.LineNumbers = true
+	if got := p.cssOptions(); !strings.Contains(got, "counter-increment: line") {
+		t.Errorf("expected line-numbering CSS when enabled, got %q", got)
+	}
+}
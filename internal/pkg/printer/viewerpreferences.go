@@ -0,0 +1,115 @@
+package printer
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+// Supported PdfViewerPreferences.PageLayout values.
+const (
+	PageLayoutSinglePage     string = "SinglePage"
+	PageLayoutOneColumn      string = "OneColumn"
+	PageLayoutTwoColumnLeft  string = "TwoColumnLeft"
+	PageLayoutTwoColumnRight string = "TwoColumnRight"
+	PageLayoutTwoPageLeft    string = "TwoPageLeft"
+	PageLayoutTwoPageRight   string = "TwoPageRight"
+)
+
+// Supported PdfViewerPreferences.PageMode values.
+const (
+	PageModeUseNone        string = "UseNone"
+	PageModeUseOutlines    string = "UseOutlines"
+	PageModeUseThumbs      string = "UseThumbs"
+	PageModeFullScreen     string = "FullScreen"
+	PageModeUseOC          string = "UseOC"
+	PageModeUseAttachments string = "UseAttachments"
+)
+
+// PdfViewerPreferences lets callers control how a PDF
+// viewer should present the document when it is opened.
+//
+// TODO: this tree has no handler/form-parsing package to wire
+// "pdfViewerPreferences.pageLayout"/"pageMode" request form
+// fields into this struct; until that package exists here, it
+// is reachable only by constructing *PrinterOptions directly.
+type PdfViewerPreferences struct {
+	PageLayout string
+	PageMode   string
+}
+
+// DefaultPdfViewerPreferences returns viewer preferences
+// that leave the document catalog untouched.
+func DefaultPdfViewerPreferences() PdfViewerPreferences {
+	return PdfViewerPreferences{
+		PageLayout: "",
+		PageMode:   "",
+	}
+}
+
+// applyViewerPreferences rewrites the document catalog of
+// the PDF at fpath so that it carries the given /PageLayout
+// and /PageMode entries. It is a no-op if prefs is empty.
+func applyViewerPreferences(fpath string, prefs PdfViewerPreferences) error {
+	const op string = "printer.applyViewerPreferences"
+	if prefs.PageLayout == "" && prefs.PageMode == "" {
+		return nil
+	}
+	if prefs.PageLayout != "" {
+		if _, ok := pageLayouts[prefs.PageLayout]; !ok {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("'%s' is not a valid page layout", prefs.PageLayout),
+				nil,
+			)
+		}
+	}
+	if prefs.PageMode != "" {
+		if _, ok := pageModes[prefs.PageMode]; !ok {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("'%s' is not a valid page mode", prefs.PageMode),
+				nil,
+			)
+		}
+	}
+	data, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	rev, err := readLatestRevision(data)
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	entries := make(map[string]string)
+	if prefs.PageLayout != "" {
+		entries["PageLayout"] = "/" + prefs.PageLayout
+	}
+	if prefs.PageMode != "" {
+		entries["PageMode"] = "/" + prefs.PageMode
+	}
+	out := appendIncrementalUpdate(data, rev, setCatalogEntries(rev.dict, entries))
+	if err := ioutil.WriteFile(fpath, out, 0600); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+var pageLayouts = map[string]struct{}{
+	PageLayoutSinglePage:     {},
+	PageLayoutOneColumn:      {},
+	PageLayoutTwoColumnLeft:  {},
+	PageLayoutTwoColumnRight: {},
+	PageLayoutTwoPageLeft:    {},
+	PageLayoutTwoPageRight:   {},
+}
+
+var pageModes = map[string]struct{}{
+	PageModeUseNone:        {},
+	PageModeUseOutlines:    {},
+	PageModeUseThumbs:      {},
+	PageModeFullScreen:     {},
+	PageModeUseOC:          {},
+	PageModeUseAttachments: {},
+}
@@ -0,0 +1,105 @@
+package printer
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+const minimalClassicPdf = `%PDF-1.4
+1 0 obj
+<< /Type /Catalog /Pages 2 0 R >>
+endobj
+2 0 obj
+<< /Type /Pages /Kids [] /Count 0 >>
+endobj
+xref
+0 3
+0000000000 65535 f
+0000000009 00000 n
+0000000058 00000 n
+trailer
+<< /Size 3 /Root 1 0 R >>
+startxref
+110
+%%EOF
+`
+
+func TestApplyViewerPreferences(t *testing.T) {
+	f, err := ioutil.TempFile("", "gotenberg-test-*.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(minimalClassicPdf); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	prefs := PdfViewerPreferences{PageLayout: PageLayoutTwoColumnLeft, PageMode: PageModeFullScreen}
+	if err := applyViewerPreferences(f.Name(), prefs); err != nil {
+		t.Fatalf("applyViewerPreferences returned an error: %s", err.Error())
+	}
+
+	out, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(out)
+	if !strings.Contains(content, "/PageLayout /TwoColumnLeft") {
+		t.Errorf("expected output to contain the new /PageLayout entry, got:\n%s", content)
+	}
+	if !strings.Contains(content, "/PageMode /FullScreen") {
+		t.Errorf("expected output to contain the new /PageMode entry, got:\n%s", content)
+	}
+	// the original revision must be left untouched: no existing
+	// byte offset should have moved.
+	if !strings.HasPrefix(content, minimalClassicPdf) {
+		t.Error("expected the original revision bytes to be preserved verbatim")
+	}
+	if !strings.Contains(content, "/Prev 110") {
+		t.Errorf("expected the new trailer to chain back to the original via /Prev, got:\n%s", content)
+	}
+}
+
+func TestApplyViewerPreferencesNoOp(t *testing.T) {
+	f, err := ioutil.TempFile("", "gotenberg-test-*.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(minimalClassicPdf); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if err := applyViewerPreferences(f.Name(), DefaultPdfViewerPreferences()); err != nil {
+		t.Fatalf("applyViewerPreferences returned an error: %s", err.Error())
+	}
+	out, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != minimalClassicPdf {
+		t.Error("expected the file to be left untouched when prefs is empty")
+	}
+}
+
+func TestApplyViewerPreferencesRejectsXrefStreamPdf(t *testing.T) {
+	f, err := ioutil.TempFile("", "gotenberg-test-*.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	// no "trailer" keyword: mimics a PDF 1.5+ cross-reference stream.
+	if _, err := f.WriteString("%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\nendobj\nstartxref\n42\n%%EOF\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	err = applyViewerPreferences(f.Name(), PdfViewerPreferences{PageLayout: PageLayoutSinglePage})
+	if err == nil {
+		t.Fatal("expected an error for a PDF with no classic trailer")
+	}
+}